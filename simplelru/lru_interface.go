@@ -0,0 +1,74 @@
+package simplelru
+
+import "time"
+
+// LRUCache is the interface for simple LRU cache implementations in this
+// package. Holding values behind this interface, rather than a concrete
+// *LRUTtl pointer, lets a cache-of-caches (a 2Q or ARC style policy, for
+// example) plug in an alternative backend such as a TTL-aware or sharded
+// cache without caring which one it got.
+type LRUCache interface {
+	// Add adds a value to the cache, returns true if an eviction occurred
+	// and updates the "recently used"-ness of the key.
+	Add(key, value interface{}) bool
+
+	// Get returns the key value, updates the "recently used"-ness of the
+	// key and returns ok true if found.
+	Get(key interface{}) (value interface{}, ok bool)
+
+	// Contains checks for the existence of a key, without updating the
+	// recent-ness or deleting it for being stale.
+	Contains(key interface{}) bool
+
+	// Peek returns the key value without updating the "recently used"-ness
+	// of the key.
+	Peek(key interface{}) (value interface{}, ok bool)
+
+	// ContainsOrAdd checks if a key is already in the cache without
+	// updating its recent-ness, and if it is not, adds it. Returns whether
+	// the key was already present and whether the add evicted an entry.
+	ContainsOrAdd(key, value interface{}) (ok, evicted bool)
+
+	// Remove removes a key from the cache, returning whether the key was
+	// contained.
+	Remove(key interface{}) bool
+
+	// RemoveOldest removes the oldest entry from the cache.
+	RemoveOldest() (key, value interface{}, ok bool)
+
+	// GetOldest returns the oldest entry from the cache.
+	GetOldest() (key, value interface{}, ok bool)
+
+	// Keys returns a slice of the keys in the cache, from oldest to
+	// newest.
+	Keys() []interface{}
+
+	// Len returns the number of items in the cache.
+	Len() int
+
+	// Purge clears all cache entries.
+	Purge()
+
+	// Resize changes the cache size and returns the number of entries
+	// dropped to make the cache shrink.
+	Resize(int) int
+}
+
+// LRUCacheFactory builds an LRUCache-compatible backend of the given size.
+// Callers wiring up a 2Q- or ARC-style cache of caches can supply a factory
+// to control which LRUCache implementation backs each of its internal
+// lists, e.g. swap in a TTL-aware cache instead of the plain one.
+type LRUCacheFactory func(size int) (LRUCache, error)
+
+var (
+	_ LRUCache = (*LRUTtl)(nil)
+	_ LRUCache = (*SieveTtl)(nil)
+)
+
+// NewLRUCacheFactory returns an LRUCacheFactory that builds LRUTtl backends
+// sharing the given expiry and eviction callback.
+func NewLRUCacheFactory(expiry time.Duration, onEvict EvictCallbackTtl) LRUCacheFactory {
+	return func(size int) (LRUCache, error) {
+		return NewLRUTtl(size, expiry, onEvict)
+	}
+}
@@ -0,0 +1,58 @@
+package simplelru
+
+import (
+	"testing"
+	"time"
+
+	v2simplelru "github.com/gopheros/golang-lru/v2/simplelru"
+)
+
+func TestLRUTtlWrapperDelegatesToCore(t *testing.T) {
+	c, err := NewLRUTtl(2, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewLRUTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.Add("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v, want 1, true", v, ok)
+	}
+	if !c.Remove("a") {
+		t.Fatal("Remove(a) = false, want true")
+	}
+}
+
+func TestLRUTtlWrapperSatisfiesLRUCache(t *testing.T) {
+	var _ LRUCache = (*LRUTtl)(nil)
+}
+
+// BenchmarkLRUTtlAdd_Legacy exercises the interface{}-based wrapper, where
+// every int key and value is boxed into an interface{} on each Add.
+func BenchmarkLRUTtlAdd_Legacy(b *testing.B) {
+	c, err := NewLRUTtl(1024, time.Hour, nil)
+	if err != nil {
+		b.Fatalf("NewLRUTtl: %v", err)
+	}
+	defer c.Close()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Add(i%1024, i)
+	}
+}
+
+// BenchmarkLRUTtlAdd_Generic exercises the v2 generic core directly with
+// int keys and values, so no boxing allocation is needed per operation.
+func BenchmarkLRUTtlAdd_Generic(b *testing.B) {
+	c, err := v2simplelru.NewLRUTtl[int, int](1024, time.Hour, nil)
+	if err != nil {
+		b.Fatalf("NewLRUTtl: %v", err)
+	}
+	defer c.Close()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Add(i%1024, i)
+	}
+}
@@ -0,0 +1,129 @@
+package simplelru
+
+import (
+	"time"
+
+	v2simplelru "github.com/gopheros/golang-lru/v2/simplelru"
+)
+
+// SieveTtl implements a thread safe fixed size, ttl enabled cache using the
+// SIEVE eviction algorithm instead of classical LRU: a hit only flips a
+// visited bit instead of moving the entry, and eviction walks a "hand"
+// pointer over the entries, clearing visited bits until it finds one to
+// evict. It is a thin wrapper over the generic core in v2/simplelru.
+type SieveTtl struct {
+	core *v2simplelru.SieveTtl[interface{}, interface{}]
+}
+
+// NewSieveTtl constructs a SIEVE cache of the given size, whose entries
+// expire after expiry has elapsed since they were last added.
+func NewSieveTtl(size int, expiry time.Duration, onEvict EvictCallbackTtl) (*SieveTtl, error) {
+	var cb v2simplelru.EvictCallbackTtl[interface{}, interface{}]
+	if onEvict != nil {
+		cb = func(key, value interface{}) { onEvict(key, value) }
+	}
+	core, err := v2simplelru.NewSieveTtl[interface{}, interface{}](size, expiry, cb)
+	if err != nil {
+		return nil, err
+	}
+	return &SieveTtl{core: core}, nil
+}
+
+// Close stops the background sweeper goroutine. Once closed, the cache must
+// not be used again.
+func (c *SieveTtl) Close() {
+	c.core.Close()
+}
+
+// Purge is used to completely clear the cache.
+func (c *SieveTtl) Purge() {
+	c.core.Purge()
+}
+
+// Add adds a value to the cache, using the cache's default expiry. Returns
+// true if an eviction occurred.
+func (c *SieveTtl) Add(key, value interface{}) (evicted bool) {
+	return c.core.Add(key, value)
+}
+
+// AddWithTTL adds a value to the cache with a per-entry TTL, overriding the
+// cache's default expiry for this key. Returns true if an eviction occurred.
+func (c *SieveTtl) AddWithTTL(key, value interface{}, ttl time.Duration) (evicted bool) {
+	return c.core.AddWithTTL(key, value, ttl)
+}
+
+// AddWithExpiresAt adds a value to the cache that expires at the given
+// deadline, overriding the cache's default expiry for this key. Returns
+// true if an eviction occurred.
+func (c *SieveTtl) AddWithExpiresAt(key, value interface{}, deadline time.Time) (evicted bool) {
+	return c.core.AddWithExpiresAt(key, value, deadline)
+}
+
+// Get looks up a key's value from the cache. A hit only sets the visited
+// bit; it never moves the entry.
+func (c *SieveTtl) Get(key interface{}) (value interface{}, ok bool) {
+	return c.core.Get(key)
+}
+
+// Contains checks if a key is in the cache, without updating its visited bit
+// or deleting it for being stale.
+func (c *SieveTtl) Contains(key interface{}) (ok bool) {
+	return c.core.Contains(key)
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the entry's visited bit.
+func (c *SieveTtl) Peek(key interface{}) (value interface{}, ok bool) {
+	return c.core.Peek(key)
+}
+
+// GetOldest returns the least recently inserted entry. SIEVE never reorders
+// entries on access, so this is insertion order, not recency of use.
+func (c *SieveTtl) GetOldest() (key, value interface{}, ok bool) {
+	return c.core.GetOldest()
+}
+
+// RemoveOldest removes the least recently inserted entry from the cache.
+func (c *SieveTtl) RemoveOldest() (key, value interface{}, ok bool) {
+	return c.core.RemoveOldest()
+}
+
+// ExpiresAt returns the deadline at which key will expire.
+func (c *SieveTtl) ExpiresAt(key interface{}) (deadline time.Time, ok bool) {
+	return c.core.ExpiresAt(key)
+}
+
+// TTL returns the remaining lifetime of key.
+func (c *SieveTtl) TTL(key interface{}) (ttl time.Duration, ok bool) {
+	return c.core.TTL(key)
+}
+
+// ContainsOrAdd checks if key is already in the cache without updating its
+// visited bit, and if it is not, adds it with the cache's default expiry.
+// Returns whether the key was already present and whether the add evicted
+// an entry.
+func (c *SieveTtl) ContainsOrAdd(key, value interface{}) (ok, evicted bool) {
+	return c.core.ContainsOrAdd(key, value)
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *SieveTtl) Remove(key interface{}) (present bool) {
+	return c.core.Remove(key)
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest
+// insertion.
+func (c *SieveTtl) Keys() []interface{} {
+	return c.core.Keys()
+}
+
+// Len returns the number of items in the cache.
+func (c *SieveTtl) Len() int {
+	return c.core.Len()
+}
+
+// Resize changes the cache size.
+func (c *SieveTtl) Resize(size int) (evicted int) {
+	return c.core.Resize(size)
+}
@@ -0,0 +1,26 @@
+package simplelru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSieveTtlWrapperDelegatesToCore(t *testing.T) {
+	c, err := NewSieveTtl(2, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewSieveTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.Add("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v, want 1, true", v, ok)
+	}
+	if !c.Remove("a") {
+		t.Fatal("Remove(a) = false, want true")
+	}
+}
+
+func TestSieveTtlWrapperSatisfiesLRUCache(t *testing.T) {
+	var _ LRUCache = (*SieveTtl)(nil)
+}
@@ -0,0 +1,412 @@
+// Package simplelru provides the generic building blocks (LRUTtl, SieveTtl)
+// that the root github.com/gopheros/golang-lru module wraps for its legacy
+// interface{}-based API.
+package simplelru
+
+import (
+	"container/heap"
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// EvictCallbackTtl is used to get a callback when a cache entry is evicted.
+type EvictCallbackTtl[K comparable, V any] func(key K, value V)
+
+// LRUTtl implements a thread safe fixed size, ttl enabled LRU cache.
+type LRUTtl[K comparable, V any] struct {
+	size      int
+	expiry    time.Duration
+	evictList *list.List
+	items     map[K]*list.Element
+	onEvict   EvictCallbackTtl[K, V]
+
+	lock sync.Mutex
+
+	expHeap entryTtlHeap[K, V]
+	sweeper *ttlSweeper
+}
+
+// entryTtl is used to hold a value in the evictList.
+type entryTtl[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+	heapIndex int
+}
+
+// entryTtlHeap is a container/heap of *entryTtl ordered by expiresAt, used by
+// the sweeper goroutine to find the next entry due to expire without
+// scanning the whole cache.
+type entryTtlHeap[K comparable, V any] []*entryTtl[K, V]
+
+func (h entryTtlHeap[K, V]) Len() int { return len(h) }
+
+func (h entryTtlHeap[K, V]) Less(i, j int) bool {
+	return h[i].expiresAt.Before(h[j].expiresAt)
+}
+
+func (h entryTtlHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *entryTtlHeap[K, V]) Push(x interface{}) {
+	ent := x.(*entryTtl[K, V])
+	ent.heapIndex = len(*h)
+	*h = append(*h, ent)
+}
+
+func (h *entryTtlHeap[K, V]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	ent := old[n-1]
+	old[n-1] = nil
+	ent.heapIndex = -1
+	*h = old[:n-1]
+	return ent
+}
+
+// NewLRUTtl constructs an LRU of the given size, whose entries expire after
+// expiry has elapsed since they were last added.
+func NewLRUTtl[K comparable, V any](size int, expiry time.Duration, onEvict EvictCallbackTtl[K, V]) (*LRUTtl[K, V], error) {
+	if size <= 0 {
+		return nil, errors.New("Must provide a positive size")
+	}
+	c := &LRUTtl[K, V]{
+		size:      size,
+		expiry:    expiry,
+		evictList: list.New(),
+		items:     make(map[K]*list.Element),
+		onEvict:   onEvict,
+	}
+	c.sweeper = newTtlSweeper(c.nextExpiry, c.reapExpired)
+	return c, nil
+}
+
+// Close stops the background sweeper goroutine. Once closed, the cache must
+// not be used again.
+func (c *LRUTtl[K, V]) Close() {
+	c.sweeper.Close()
+}
+
+// nextExpiry returns how long the sweeper should sleep before its next pass.
+func (c *LRUTtl[K, V]) nextExpiry() time.Duration {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.expHeap) == 0 {
+		return time.Hour
+	}
+	d := time.Until(c.expHeap[0].expiresAt)
+	if d <= 0 {
+		return time.Millisecond
+	}
+	return d
+}
+
+// reapExpired removes every entry whose TTL has elapsed. onEvict is invoked
+// outside of the write-lock so that a callback touching the cache can't
+// deadlock against the sweeper.
+func (c *LRUTtl[K, V]) reapExpired() {
+	var evicted []*entryTtl[K, V]
+
+	c.lock.Lock()
+	now := time.Now()
+	for len(c.expHeap) > 0 && !c.expHeap[0].expiresAt.After(now) {
+		ent := heap.Pop(&c.expHeap).(*entryTtl[K, V])
+		if el, ok := c.items[ent.key]; ok {
+			c.evictList.Remove(el)
+			delete(c.items, ent.key)
+			evicted = append(evicted, ent)
+		}
+	}
+	c.lock.Unlock()
+
+	if c.onEvict != nil {
+		for _, ent := range evicted {
+			c.onEvict(ent.key, ent.value)
+		}
+	}
+}
+
+// Purge is used to completely clear the cache.
+func (c *LRUTtl[K, V]) Purge() {
+	var evicted []*entryTtl[K, V]
+
+	c.lock.Lock()
+	for _, el := range c.items {
+		evicted = append(evicted, el.Value.(*entryTtl[K, V]))
+	}
+	c.items = make(map[K]*list.Element)
+	c.evictList.Init()
+	c.expHeap = nil
+	c.lock.Unlock()
+
+	if c.onEvict != nil {
+		for _, ent := range evicted {
+			c.onEvict(ent.key, ent.value)
+		}
+	}
+}
+
+// Add adds a value to the cache, using the cache's default expiry. Returns
+// true if an eviction occurred.
+func (c *LRUTtl[K, V]) Add(key K, value V) (evicted bool) {
+	return c.addWithExpiresAt(key, value, time.Now().Add(c.expiry))
+}
+
+// AddWithTTL adds a value to the cache with a per-entry TTL, overriding the
+// cache's default expiry for this key. Returns true if an eviction occurred.
+func (c *LRUTtl[K, V]) AddWithTTL(key K, value V, ttl time.Duration) (evicted bool) {
+	return c.addWithExpiresAt(key, value, time.Now().Add(ttl))
+}
+
+// AddWithExpiresAt adds a value to the cache that expires at the given
+// deadline, overriding the cache's default expiry for this key. Returns
+// true if an eviction occurred.
+func (c *LRUTtl[K, V]) AddWithExpiresAt(key K, value V, deadline time.Time) (evicted bool) {
+	return c.addWithExpiresAt(key, value, deadline)
+}
+
+// addWithExpiresAt is the shared implementation behind Add and the
+// per-entry TTL variants.
+func (c *LRUTtl[K, V]) addWithExpiresAt(key K, value V, deadline time.Time) (evicted bool) {
+	c.lock.Lock()
+	evict, displaced := c.addWithExpiresAtLocked(key, value, deadline)
+	c.lock.Unlock()
+	c.sweeper.wakeNow()
+
+	if displaced != nil && c.onEvict != nil {
+		c.onEvict(displaced.key, displaced.value)
+	}
+	return evict
+}
+
+// addWithExpiresAtLocked is addWithExpiresAt with the locking pulled out, so
+// that callers needing to combine it with another locked check (such as
+// ContainsOrAdd) can do both atomically under a single critical section.
+// c.lock must already be held; any returned displaced entry's onEvict call
+// is the caller's responsibility.
+func (c *LRUTtl[K, V]) addWithExpiresAtLocked(key K, value V, deadline time.Time) (evicted bool, displaced *entryTtl[K, V]) {
+	// Check for existing item
+	if el, ok := c.items[key]; ok {
+		c.evictList.MoveToFront(el)
+		ent := el.Value.(*entryTtl[K, V])
+		ent.value = value
+		ent.expiresAt = deadline
+		heap.Fix(&c.expHeap, ent.heapIndex)
+		return false, nil
+	}
+
+	// Add new item
+	ent := &entryTtl[K, V]{
+		key:       key,
+		value:     value,
+		expiresAt: deadline,
+	}
+	heap.Push(&c.expHeap, ent)
+	el := c.evictList.PushFront(ent)
+	c.items[key] = el
+
+	evict := c.evictList.Len() > c.size
+	if evict {
+		displaced = c.removeOldestLocked()
+	}
+	return evict, displaced
+}
+
+// Get looks up a key's value from the cache.
+func (c *LRUTtl[K, V]) Get(key K) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	ent := el.Value.(*entryTtl[K, V])
+	if time.Now().After(ent.expiresAt) {
+		return value, false
+	}
+	c.evictList.MoveToFront(el)
+	return ent.value, true
+}
+
+// Contains checks if a key is in the cache, without updating the recent-ness
+// or deleting it for being stale.
+func (c *LRUTtl[K, V]) Contains(key K) (ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, ok = c.items[key]
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the "recently used"-ness of the key.
+func (c *LRUTtl[K, V]) Peek(key K) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if el, ok := c.items[key]; ok {
+		return el.Value.(*entryTtl[K, V]).value, true
+	}
+	return value, false
+}
+
+// ExpiresAt returns the deadline at which key will expire, without updating
+// the "recently used"-ness of the key.
+func (c *LRUTtl[K, V]) ExpiresAt(key K) (deadline time.Time, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if el, ok := c.items[key]; ok {
+		return el.Value.(*entryTtl[K, V]).expiresAt, true
+	}
+	return deadline, false
+}
+
+// TTL returns the remaining lifetime of key, without updating the
+// "recently used"-ness of the key. A key that has already expired but has
+// not yet been swept reports a zero or negative duration.
+func (c *LRUTtl[K, V]) TTL(key K) (ttl time.Duration, ok bool) {
+	deadline, ok := c.ExpiresAt(key)
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// ContainsOrAdd checks if key is already in the cache without updating its
+// recent-ness, and if it is not, adds it with the cache's default expiry.
+// Returns whether the key was already present and whether the add evicted
+// an entry.
+func (c *LRUTtl[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
+	c.lock.Lock()
+	if _, ok := c.items[key]; ok {
+		c.lock.Unlock()
+		return true, false
+	}
+	evicted, displaced := c.addWithExpiresAtLocked(key, value, time.Now().Add(c.expiry))
+	c.lock.Unlock()
+	c.sweeper.wakeNow()
+
+	if displaced != nil && c.onEvict != nil {
+		c.onEvict(displaced.key, displaced.value)
+	}
+	return false, evicted
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *LRUTtl[K, V]) Remove(key K) (present bool) {
+	var ent *entryTtl[K, V]
+
+	c.lock.Lock()
+	if el, ok := c.items[key]; ok {
+		ent = c.removeElementLocked(el)
+	}
+	c.lock.Unlock()
+
+	if ent != nil && c.onEvict != nil {
+		c.onEvict(ent.key, ent.value)
+	}
+	return ent != nil
+}
+
+// RemoveOldest removes the oldest item from the cache.
+func (c *LRUTtl[K, V]) RemoveOldest() (key K, value V, ok bool) {
+	var ent *entryTtl[K, V]
+
+	c.lock.Lock()
+	el := c.evictList.Back()
+	if el != nil {
+		ent = c.removeElementLocked(el)
+	}
+	c.lock.Unlock()
+
+	if ent == nil {
+		return key, value, false
+	}
+	if c.onEvict != nil {
+		c.onEvict(ent.key, ent.value)
+	}
+	return ent.key, ent.value, true
+}
+
+// GetOldest returns the oldest entry.
+func (c *LRUTtl[K, V]) GetOldest() (key K, value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	el := c.evictList.Back()
+	if el != nil {
+		ent := el.Value.(*entryTtl[K, V])
+		return ent.key, ent.value, true
+	}
+	return key, value, false
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest.
+func (c *LRUTtl[K, V]) Keys() []K {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	keys := make([]K, 0, len(c.items))
+	for el := c.evictList.Back(); el != nil; el = el.Prev() {
+		keys = append(keys, el.Value.(*entryTtl[K, V]).key)
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *LRUTtl[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.evictList.Len()
+}
+
+// Resize changes the cache size.
+func (c *LRUTtl[K, V]) Resize(size int) (evicted int) {
+	var removed []*entryTtl[K, V]
+
+	c.lock.Lock()
+	diff := c.evictList.Len() - size
+	if diff < 0 {
+		diff = 0
+	}
+	for i := 0; i < diff; i++ {
+		if el := c.evictList.Back(); el != nil {
+			removed = append(removed, c.removeElementLocked(el))
+		}
+	}
+	c.size = size
+	c.lock.Unlock()
+
+	if c.onEvict != nil {
+		for _, ent := range removed {
+			c.onEvict(ent.key, ent.value)
+		}
+	}
+	return len(removed)
+}
+
+// removeOldestLocked removes the oldest item from the cache. c.lock must be
+// held by the caller, and onEvict is run by the caller (outside the lock)
+// since removeOldestLocked itself never unlocks.
+func (c *LRUTtl[K, V]) removeOldestLocked() *entryTtl[K, V] {
+	el := c.evictList.Back()
+	if el == nil {
+		return nil
+	}
+	return c.removeElementLocked(el)
+}
+
+// removeElementLocked removes a given list element from the cache. c.lock
+// must already be held; onEvict is the caller's responsibility.
+func (c *LRUTtl[K, V]) removeElementLocked(el *list.Element) *entryTtl[K, V] {
+	c.evictList.Remove(el)
+	ent := el.Value.(*entryTtl[K, V])
+	delete(c.items, ent.key)
+	if ent.heapIndex >= 0 {
+		heap.Remove(&c.expHeap, ent.heapIndex)
+	}
+	return ent
+}
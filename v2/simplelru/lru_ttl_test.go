@@ -0,0 +1,152 @@
+package simplelru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRUTtlAddGetRemove(t *testing.T) {
+	c, err := NewLRUTtl[int, int](2, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewLRUTtl: %v", err)
+	}
+	defer c.Close()
+
+	if evicted := c.Add(1, 10); evicted {
+		t.Fatal("unexpected eviction on first add")
+	}
+	if v, ok := c.Get(1); !ok || v != 10 {
+		t.Fatalf("Get(1) = %v, %v, want 10, true", v, ok)
+	}
+	if !c.Contains(1) {
+		t.Fatal("Contains(1) = false, want true")
+	}
+	if v, ok := c.Peek(1); !ok || v != 10 {
+		t.Fatalf("Peek(1) = %v, %v, want 10, true", v, ok)
+	}
+	if !c.Remove(1) {
+		t.Fatal("Remove(1) = false, want true")
+	}
+	if _, ok := c.Get(1); ok {
+		t.Fatal("Get(1) after Remove found a value")
+	}
+}
+
+func TestLRUTtlEvictsOldestOverCapacity(t *testing.T) {
+	var evictedKey int
+	c, err := NewLRUTtl[int, int](2, time.Hour, func(key, value int) { evictedKey = key })
+	if err != nil {
+		t.Fatalf("NewLRUTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+	c.Get(1) // touch 1 so 2 is the least recently used
+	if evicted := c.Add(3, 3); !evicted {
+		t.Fatal("Add(3) over capacity should report an eviction")
+	}
+	if evictedKey != 2 {
+		t.Fatalf("evicted key = %d, want 2", evictedKey)
+	}
+	if c.Contains(2) {
+		t.Fatal("key 2 should have been evicted")
+	}
+}
+
+func TestLRUTtlExpiry(t *testing.T) {
+	evicted := make(chan int, 1)
+	c, err := NewLRUTtl[int, int](2, 10*time.Millisecond, func(key, value int) { evicted <- key })
+	if err != nil {
+		t.Fatalf("NewLRUTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.Add(1, 1)
+	select {
+	case key := <-evicted:
+		if key != 1 {
+			t.Fatalf("evicted key = %d, want 1", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("entry did not expire in time")
+	}
+	if _, ok := c.Get(1); ok {
+		t.Fatal("Get(1) found an expired entry")
+	}
+}
+
+func TestLRUTtlAddWithTTLOverridesDefault(t *testing.T) {
+	c, err := NewLRUTtl[int, int](2, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewLRUTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.AddWithTTL(1, 1, 10*time.Millisecond)
+	ttl, ok := c.TTL(1)
+	if !ok || ttl > 10*time.Millisecond {
+		t.Fatalf("TTL(1) = %v, %v, want <= 10ms, true", ttl, ok)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := c.Get(1); ok {
+		t.Fatal("Get(1) found an entry past its per-entry TTL")
+	}
+}
+
+func TestLRUTtlContainsOrAddIsAtomic(t *testing.T) {
+	c, err := NewLRUTtl[int, int](100, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewLRUTtl: %v", err)
+	}
+	defer c.Close()
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	added := make([]bool, goroutines)
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			ok, _ := c.ContainsOrAdd(1, i)
+			added[i] = !ok
+		}()
+	}
+	wg.Wait()
+
+	var winners int
+	for _, first := range added {
+		if first {
+			winners++
+		}
+	}
+	if winners != 1 {
+		t.Fatalf("ContainsOrAdd reported %d first-insert winners, want exactly 1", winners)
+	}
+}
+
+func TestLRUTtlPurgeAndResize(t *testing.T) {
+	c, err := NewLRUTtl[int, int](4, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewLRUTtl: %v", err)
+	}
+	defer c.Close()
+
+	for i := 0; i < 4; i++ {
+		c.Add(i, i)
+	}
+	if n := c.Resize(2); n != 2 {
+		t.Fatalf("Resize(2) evicted %d entries, want 2", n)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", c.Len())
+	}
+
+	c.Purge()
+	if c.Len() != 0 {
+		t.Fatalf("Len() after Purge = %d, want 0", c.Len())
+	}
+}
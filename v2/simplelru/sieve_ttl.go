@@ -0,0 +1,435 @@
+package simplelru
+
+import (
+	"container/heap"
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// SieveTtl implements a thread safe fixed size, ttl enabled cache using the
+// SIEVE eviction algorithm instead of classical LRU. Unlike LRUTtl, a Get
+// never reorders the entry list: it only flips the entry's visited bit, so
+// hits are cheap and do not contend on list pointer updates.
+type SieveTtl[K comparable, V any] struct {
+	size    int
+	expiry  time.Duration
+	entries *list.List
+	items   map[K]*list.Element
+	onEvict EvictCallbackTtl[K, V]
+	hand    *list.Element
+
+	lock sync.Mutex
+
+	expHeap entrySieveHeap[K, V]
+	sweeper *ttlSweeper
+}
+
+// entrySieve is used to hold a value in the entries list.
+type entrySieve[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+	heapIndex int
+	visited   bool
+}
+
+// entrySieveHeap is a container/heap of *entrySieve ordered by expiresAt,
+// used by the sweeper goroutine to find the next entry due to expire
+// without scanning the whole cache. It mirrors entryTtlHeap; the two can't
+// share a type because Go generics don't let a heap be generic over the
+// element's own field layout independently of its type parameters.
+type entrySieveHeap[K comparable, V any] []*entrySieve[K, V]
+
+func (h entrySieveHeap[K, V]) Len() int { return len(h) }
+
+func (h entrySieveHeap[K, V]) Less(i, j int) bool {
+	return h[i].expiresAt.Before(h[j].expiresAt)
+}
+
+func (h entrySieveHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *entrySieveHeap[K, V]) Push(x interface{}) {
+	ent := x.(*entrySieve[K, V])
+	ent.heapIndex = len(*h)
+	*h = append(*h, ent)
+}
+
+func (h *entrySieveHeap[K, V]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	ent := old[n-1]
+	old[n-1] = nil
+	ent.heapIndex = -1
+	*h = old[:n-1]
+	return ent
+}
+
+// NewSieveTtl constructs a SIEVE cache of the given size, whose entries
+// expire after expiry has elapsed since they were last added.
+func NewSieveTtl[K comparable, V any](size int, expiry time.Duration, onEvict EvictCallbackTtl[K, V]) (*SieveTtl[K, V], error) {
+	if size <= 0 {
+		return nil, errors.New("Must provide a positive size")
+	}
+	c := &SieveTtl[K, V]{
+		size:    size,
+		expiry:  expiry,
+		entries: list.New(),
+		items:   make(map[K]*list.Element),
+		onEvict: onEvict,
+	}
+	c.sweeper = newTtlSweeper(c.nextExpiry, c.reapExpired)
+	return c, nil
+}
+
+// Close stops the sweeper goroutine started in NewSieveTtl. Once closed,
+// the cache must not be used again.
+func (c *SieveTtl[K, V]) Close() {
+	c.sweeper.Close()
+}
+
+// nextExpiry reports the delay before expHeap's earliest entry expires, for
+// the shared sweeper to sleep on.
+func (c *SieveTtl[K, V]) nextExpiry() time.Duration {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.expHeap) == 0 {
+		return time.Hour
+	}
+	d := time.Until(c.expHeap[0].expiresAt)
+	if d <= 0 {
+		return time.Millisecond
+	}
+	return d
+}
+
+// reapExpired pops every entry off expHeap that is due by now, detaching
+// each from entries/items regardless of its visited bit or hand position.
+// onEvict runs after c.lock is released so a callback that touches the
+// cache can't deadlock against the sweeper.
+func (c *SieveTtl[K, V]) reapExpired() {
+	var evicted []*entrySieve[K, V]
+
+	c.lock.Lock()
+	now := time.Now()
+	for len(c.expHeap) > 0 && !c.expHeap[0].expiresAt.After(now) {
+		ent := heap.Pop(&c.expHeap).(*entrySieve[K, V])
+		if el, ok := c.items[ent.key]; ok {
+			c.removeListElementLocked(el)
+			evicted = append(evicted, ent)
+		}
+	}
+	c.lock.Unlock()
+
+	if c.onEvict != nil {
+		for _, ent := range evicted {
+			c.onEvict(ent.key, ent.value)
+		}
+	}
+}
+
+// Purge evicts every entry and resets the hand, completely clearing the
+// cache.
+func (c *SieveTtl[K, V]) Purge() {
+	var evicted []*entrySieve[K, V]
+
+	c.lock.Lock()
+	for _, el := range c.items {
+		evicted = append(evicted, el.Value.(*entrySieve[K, V]))
+	}
+	c.items = make(map[K]*list.Element)
+	c.entries.Init()
+	c.expHeap = nil
+	c.hand = nil
+	c.lock.Unlock()
+
+	if c.onEvict != nil {
+		for _, ent := range evicted {
+			c.onEvict(ent.key, ent.value)
+		}
+	}
+}
+
+// Add adds a value to the cache, using the cache's default expiry. Returns
+// true if an eviction occurred.
+func (c *SieveTtl[K, V]) Add(key K, value V) (evicted bool) {
+	return c.addWithExpiresAt(key, value, time.Now().Add(c.expiry))
+}
+
+// AddWithTTL adds a value to the cache with a per-entry TTL, overriding the
+// cache's default expiry for this key. Returns true if an eviction occurred.
+func (c *SieveTtl[K, V]) AddWithTTL(key K, value V, ttl time.Duration) (evicted bool) {
+	return c.addWithExpiresAt(key, value, time.Now().Add(ttl))
+}
+
+// AddWithExpiresAt adds a value to the cache that expires at the given
+// deadline, overriding the cache's default expiry for this key. Returns
+// true if an eviction occurred.
+func (c *SieveTtl[K, V]) AddWithExpiresAt(key K, value V, deadline time.Time) (evicted bool) {
+	return c.addWithExpiresAt(key, value, deadline)
+}
+
+func (c *SieveTtl[K, V]) addWithExpiresAt(key K, value V, deadline time.Time) (evicted bool) {
+	c.lock.Lock()
+	evict, displaced := c.addWithExpiresAtLocked(key, value, deadline)
+	c.lock.Unlock()
+	c.sweeper.wakeNow()
+
+	if displaced != nil && c.onEvict != nil {
+		c.onEvict(displaced.key, displaced.value)
+	}
+	return evict
+}
+
+// addWithExpiresAtLocked is addWithExpiresAt with the locking pulled out, so
+// that callers needing to combine it with another locked check (such as
+// ContainsOrAdd) can do both atomically under a single critical section.
+// c.lock must already be held; any returned displaced entry's onEvict call
+// is the caller's responsibility.
+func (c *SieveTtl[K, V]) addWithExpiresAtLocked(key K, value V, deadline time.Time) (evicted bool, displaced *entrySieve[K, V]) {
+	// A write to an existing key is treated as a hit: refresh the value and
+	// expiry and mark it visited, but leave its position alone.
+	if el, ok := c.items[key]; ok {
+		ent := el.Value.(*entrySieve[K, V])
+		ent.value = value
+		ent.expiresAt = deadline
+		ent.visited = true
+		heap.Fix(&c.expHeap, ent.heapIndex)
+		return false, nil
+	}
+
+	evict := c.entries.Len() >= c.size
+	if evict {
+		displaced = c.evictLocked()
+	}
+
+	ent := &entrySieve[K, V]{
+		key:       key,
+		value:     value,
+		expiresAt: deadline,
+	}
+	heap.Push(&c.expHeap, ent)
+	el := c.entries.PushFront(ent)
+	c.items[key] = el
+
+	return evict, displaced
+}
+
+// evictLocked runs the SIEVE eviction algorithm: walk the hand backward from
+// its current position (wrapping to the tail), clearing visited bits until
+// an unvisited entry is found, then evict it and leave the hand on the entry
+// just before it (toward the head). c.lock must already be held.
+func (c *SieveTtl[K, V]) evictLocked() *entrySieve[K, V] {
+	hand := c.hand
+	if hand == nil {
+		hand = c.entries.Back()
+	}
+	for hand != nil {
+		ent := hand.Value.(*entrySieve[K, V])
+		if ent.visited {
+			ent.visited = false
+			hand = hand.Prev()
+			if hand == nil {
+				hand = c.entries.Back()
+			}
+			continue
+		}
+		c.hand = hand.Prev()
+		c.removeListElementLocked(hand)
+		return ent
+	}
+	return nil
+}
+
+// Get looks up a key's value from the cache. A hit only sets the visited
+// bit; it never moves the entry.
+func (c *SieveTtl[K, V]) Get(key K) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	ent := el.Value.(*entrySieve[K, V])
+	if time.Now().After(ent.expiresAt) {
+		return value, false
+	}
+	ent.visited = true
+	return ent.value, true
+}
+
+// Contains checks if a key is in the cache, without updating its visited bit
+// or deleting it for being stale.
+func (c *SieveTtl[K, V]) Contains(key K) (ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, ok = c.items[key]
+	return ok
+}
+
+// Peek returns the key value (or undefined if not found) without updating
+// the entry's visited bit.
+func (c *SieveTtl[K, V]) Peek(key K) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if el, ok := c.items[key]; ok {
+		return el.Value.(*entrySieve[K, V]).value, true
+	}
+	return value, false
+}
+
+// ExpiresAt returns the deadline at which key will expire.
+func (c *SieveTtl[K, V]) ExpiresAt(key K) (deadline time.Time, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if el, ok := c.items[key]; ok {
+		return el.Value.(*entrySieve[K, V]).expiresAt, true
+	}
+	return deadline, false
+}
+
+// TTL returns the remaining lifetime of key.
+func (c *SieveTtl[K, V]) TTL(key K) (ttl time.Duration, ok bool) {
+	deadline, ok := c.ExpiresAt(key)
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// GetOldest returns the least recently inserted entry. SIEVE never reorders
+// entries on access, so this is insertion order, not recency of use.
+func (c *SieveTtl[K, V]) GetOldest() (key K, value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	el := c.entries.Back()
+	if el != nil {
+		ent := el.Value.(*entrySieve[K, V])
+		return ent.key, ent.value, true
+	}
+	return key, value, false
+}
+
+// RemoveOldest removes the least recently inserted entry from the cache.
+func (c *SieveTtl[K, V]) RemoveOldest() (key K, value V, ok bool) {
+	var ent *entrySieve[K, V]
+
+	c.lock.Lock()
+	el := c.entries.Back()
+	if el != nil {
+		ent = el.Value.(*entrySieve[K, V])
+		c.removeListElementLocked(el)
+	}
+	c.lock.Unlock()
+
+	if ent == nil {
+		return key, value, false
+	}
+	if c.onEvict != nil {
+		c.onEvict(ent.key, ent.value)
+	}
+	return ent.key, ent.value, true
+}
+
+// ContainsOrAdd checks if key is already in the cache without updating its
+// visited bit, and if it is not, adds it with the cache's default expiry.
+// Returns whether the key was already present and whether the add evicted
+// an entry.
+func (c *SieveTtl[K, V]) ContainsOrAdd(key K, value V) (ok, evicted bool) {
+	c.lock.Lock()
+	if _, ok := c.items[key]; ok {
+		c.lock.Unlock()
+		return true, false
+	}
+	evicted, displaced := c.addWithExpiresAtLocked(key, value, time.Now().Add(c.expiry))
+	c.lock.Unlock()
+	c.sweeper.wakeNow()
+
+	if displaced != nil && c.onEvict != nil {
+		c.onEvict(displaced.key, displaced.value)
+	}
+	return false, evicted
+}
+
+// Remove removes the provided key from the cache, returning if the
+// key was contained.
+func (c *SieveTtl[K, V]) Remove(key K) (present bool) {
+	var ent *entrySieve[K, V]
+
+	c.lock.Lock()
+	if el, ok := c.items[key]; ok {
+		ent = el.Value.(*entrySieve[K, V])
+		c.removeListElementLocked(el)
+	}
+	c.lock.Unlock()
+
+	if ent != nil && c.onEvict != nil {
+		c.onEvict(ent.key, ent.value)
+	}
+	return ent != nil
+}
+
+// Keys returns a slice of the keys in the cache, from oldest to newest
+// insertion.
+func (c *SieveTtl[K, V]) Keys() []K {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	keys := make([]K, 0, len(c.items))
+	for el := c.entries.Back(); el != nil; el = el.Prev() {
+		keys = append(keys, el.Value.(*entrySieve[K, V]).key)
+	}
+	return keys
+}
+
+// Len returns the number of items in the cache.
+func (c *SieveTtl[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.entries.Len()
+}
+
+// Resize changes the cache size.
+func (c *SieveTtl[K, V]) Resize(size int) (evicted int) {
+	var removed []*entrySieve[K, V]
+
+	c.lock.Lock()
+	diff := c.entries.Len() - size
+	if diff < 0 {
+		diff = 0
+	}
+	for i := 0; i < diff; i++ {
+		if ent := c.evictLocked(); ent != nil {
+			removed = append(removed, ent)
+		}
+	}
+	c.size = size
+	c.lock.Unlock()
+
+	if c.onEvict != nil {
+		for _, ent := range removed {
+			c.onEvict(ent.key, ent.value)
+		}
+	}
+	return len(removed)
+}
+
+// removeListElementLocked removes a given list element from the cache.
+// c.lock must already be held; onEvict is the caller's responsibility. If el
+// is the current hand, the hand steps back to the previous entry.
+func (c *SieveTtl[K, V]) removeListElementLocked(el *list.Element) {
+	ent := el.Value.(*entrySieve[K, V])
+	if c.hand == el {
+		c.hand = el.Prev()
+	}
+	c.entries.Remove(el)
+	delete(c.items, ent.key)
+	if ent.heapIndex >= 0 {
+		heap.Remove(&c.expHeap, ent.heapIndex)
+	}
+}
@@ -0,0 +1,145 @@
+package simplelru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSieveTtlAddGetRemove(t *testing.T) {
+	c, err := NewSieveTtl[int, int](2, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewSieveTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.Add(1, 10)
+	if v, ok := c.Get(1); !ok || v != 10 {
+		t.Fatalf("Get(1) = %v, %v, want 10, true", v, ok)
+	}
+	if !c.Remove(1) {
+		t.Fatal("Remove(1) = false, want true")
+	}
+	if _, ok := c.Get(1); ok {
+		t.Fatal("Get(1) after Remove found a value")
+	}
+}
+
+// TestSieveTtlVisitedSurvivesOneSweep exercises the hand algorithm directly:
+// a visited entry should be spared on the first pass the hand reaches it
+// (its visited bit just gets cleared) and evicted on the next.
+func TestSieveTtlVisitedSurvivesOneSweep(t *testing.T) {
+	var evictedKeys []int
+	c, err := NewSieveTtl[int, int](2, time.Hour, func(key, value int) {
+		evictedKeys = append(evictedKeys, key)
+	})
+	if err != nil {
+		t.Fatalf("NewSieveTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+	c.Get(1) // mark 1 visited
+
+	c.Add(3, 3) // hand starts at the back (1, visited): spares it, evicts 2
+	if len(evictedKeys) != 1 || evictedKeys[0] != 2 {
+		t.Fatalf("evicted = %v, want [2]", evictedKeys)
+	}
+	if !c.Contains(1) {
+		t.Fatal("key 1 should have survived the first sweep")
+	}
+
+	c.Add(4, 4) // hand resumes at 1 (now unvisited): evicts it
+	if len(evictedKeys) != 2 || evictedKeys[1] != 1 {
+		t.Fatalf("evicted = %v, want second entry to be 1", evictedKeys)
+	}
+}
+
+func TestSieveTtlGetDoesNotReorder(t *testing.T) {
+	c, err := NewSieveTtl[int, int](3, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewSieveTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+	c.Get(1)
+	c.Get(1)
+
+	key, _, ok := c.GetOldest()
+	if !ok || key != 1 {
+		t.Fatalf("GetOldest() = %v, %v, want 1, true; Get must not reorder entries", key, ok)
+	}
+}
+
+func TestSieveTtlExpiry(t *testing.T) {
+	evicted := make(chan int, 1)
+	c, err := NewSieveTtl[int, int](2, 10*time.Millisecond, func(key, value int) { evicted <- key })
+	if err != nil {
+		t.Fatalf("NewSieveTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.Add(1, 1)
+	select {
+	case key := <-evicted:
+		if key != 1 {
+			t.Fatalf("evicted key = %d, want 1", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("entry did not expire in time")
+	}
+}
+
+func TestSieveTtlContainsOrAddIsAtomic(t *testing.T) {
+	c, err := NewSieveTtl[int, int](100, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewSieveTtl: %v", err)
+	}
+	defer c.Close()
+
+	const goroutines = 50
+	var wg sync.WaitGroup
+	added := make([]bool, goroutines)
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			ok, _ := c.ContainsOrAdd(1, i)
+			added[i] = !ok
+		}()
+	}
+	wg.Wait()
+
+	var winners int
+	for _, first := range added {
+		if first {
+			winners++
+		}
+	}
+	if winners != 1 {
+		t.Fatalf("ContainsOrAdd reported %d first-insert winners, want exactly 1", winners)
+	}
+}
+
+func TestSieveTtlGetOldestAndRemoveOldest(t *testing.T) {
+	c, err := NewSieveTtl[int, int](3, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("NewSieveTtl: %v", err)
+	}
+	defer c.Close()
+
+	c.Add(1, 1)
+	c.Add(2, 2)
+
+	key, value, ok := c.RemoveOldest()
+	if !ok || key != 1 || value != 1 {
+		t.Fatalf("RemoveOldest() = %v, %v, %v, want 1, 1, true", key, value, ok)
+	}
+	if c.Contains(1) {
+		t.Fatal("key 1 should have been removed")
+	}
+}
@@ -0,0 +1,61 @@
+package simplelru
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlSweeper runs a single background goroutine that sleeps until the next
+// entry is due to expire (as reported by nextExpiry) and then calls
+// reapExpired. LRUTtl and SieveTtl each hold one so that every TTL-aware
+// cache in this package shares the same expiration machinery instead of
+// spawning a goroutine per entry.
+type ttlSweeper struct {
+	wake chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// newTtlSweeper starts the sweeper goroutine. nextExpiry and reapExpired
+// must do their own locking; the sweeper never touches the cache directly.
+func newTtlSweeper(nextExpiry func() time.Duration, reapExpired func()) *ttlSweeper {
+	s := &ttlSweeper{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go s.run(nextExpiry, reapExpired)
+	return s
+}
+
+func (s *ttlSweeper) run(nextExpiry func() time.Duration, reapExpired func()) {
+	timer := time.NewTimer(nextExpiry())
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(nextExpiry())
+		case <-timer.C:
+			reapExpired()
+			timer.Reset(nextExpiry())
+		}
+	}
+}
+
+// wakeNow nudges the sweeper into recomputing its sleep duration, without
+// blocking if it is already awake.
+func (s *ttlSweeper) wakeNow() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops the sweeper goroutine. Safe to call more than once.
+func (s *ttlSweeper) Close() {
+	s.once.Do(func() { close(s.done) })
+}